@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
@@ -27,6 +28,7 @@ import (
 	"github.com/gardener/gardener/pkg/operation/botanist/dns"
 	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
 
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +43,70 @@ const (
 	DNSProviderRoleAdditional = "managed-dns-provider"
 )
 
+// routingPolicyTypesByProvider maps a DNS provider type to the set of routing policy types it is
+// able to render. A provider that is not listed here does not support routing policies at all.
+var routingPolicyTypesByProvider = map[string]sets.String{
+	"aws-route53":     sets.NewString(string(core.DNSRoutingPolicyWeighted), string(core.DNSRoutingPolicyLatency), string(core.DNSRoutingPolicyFailover)),
+	"google-clouddns": sets.NewString(string(core.DNSRoutingPolicyWeighted), string(core.DNSRoutingPolicyGeolocation)),
+	"azure-dns":       sets.NewString(string(core.DNSRoutingPolicyWeighted)),
+}
+
+// validateRoutingPolicy checks that the given routing policy (if any) is supported by the given DNS
+// provider type.
+func validateRoutingPolicy(providerType string, policy *core.DNSRoutingPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	if supported, ok := routingPolicyTypesByProvider[providerType]; !ok || !supported.Has(string(policy.Type)) {
+		return fmt.Errorf("routing policy type %q is not supported by dns provider type %q", policy.Type, providerType)
+	}
+
+	return nil
+}
+
+// toEntryRoutingPolicy converts the shoot API's routing policy into the routing policy consumed by
+// the DNSEntry deploy path.
+func toEntryRoutingPolicy(policy *core.DNSRoutingPolicy) *dns.RoutingPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	return &dns.RoutingPolicy{
+		Type:          string(policy.Type),
+		SetIdentifier: policy.SetIdentifier,
+		Parameters:    policy.Parameters,
+	}
+}
+
+// dnsTTL returns the TTL configured on the shoot's DNS spec, or nil if the operator did not set one,
+// in which case the DNS provider's own default (300s for most cloud DNS backends) applies.
+func (b *Botanist) dnsTTL() *int64 {
+	if dns := b.Shoot.Info.Spec.DNS; dns != nil {
+		return dns.TTL
+	}
+	return nil
+}
+
+// dnsInterval returns the interval configured on the shoot's DNS spec at which the DNS provider
+// re-resolves a CNAME-based entry target, or nil if the operator did not set one, in which case the
+// DNS provider's own default applies.
+func (b *Botanist) dnsInterval() *int64 {
+	if dns := b.Shoot.Info.Spec.DNS; dns != nil {
+		return dns.Interval
+	}
+	return nil
+}
+
+// primaryRoutingPolicy returns the routing policy configured on the shoot's DNS spec, or nil if the
+// shoot does not specify one (or does not specify DNS at all).
+func (b *Botanist) primaryRoutingPolicy() *core.DNSRoutingPolicy {
+	if dns := b.Shoot.Info.Spec.DNS; dns != nil {
+		return dns.RoutingPolicy
+	}
+	return nil
+}
+
 // GenerateDNSProviderName creates a name for the dns provider out of the passed `secretName` and `providerType`.
 func GenerateDNSProviderName(secretName, providerType string) string {
 	switch {
@@ -55,6 +121,13 @@ func GenerateDNSProviderName(secretName, providerType string) string {
 	}
 }
 
+// GenerateDNSRealm generates the default realm for a shoot's DNS providers and entries, scoped to
+// the shoot's own seed namespace so that it cannot be hijacked by entries of other shoots sharing
+// the same seed namespace.
+func GenerateDNSRealm(shootNamespace string) string {
+	return fmt.Sprintf("%s/dns.gardener.cloud", shootNamespace)
+}
+
 // DeployExternalDNS deploys the external DNSProvider and DNSEntry.
 func (b *Botanist) DeployExternalDNS(ctx context.Context) error {
 	return b.ExternalDNS().Deploy(ctx)
@@ -139,8 +212,35 @@ func (b *Botanist) DefaultExternalDNSProvider(seedClient client.Client) componen
 	))
 }
 
-// DefaultExternalDNSEntry returns DeployWaiter which removes the external DNSEntry.
-func (b *Botanist) DefaultExternalDNSEntry(seedClient client.Client) component.DeployWaiter {
+// DefaultExternalDNSEntry returns the external DNSEntry if external DNS is enabled and if not, a
+// DeployWaiter which removes the external DNSEntry. It returns an error if the shoot's routing policy
+// is not supported by the external DNS provider type.
+func (b *Botanist) DefaultExternalDNSEntry(seedClient client.Client) (component.DeployWaiter, error) {
+	if b.NeedsExternalDNS() {
+		policy := b.primaryRoutingPolicy()
+		if err := validateRoutingPolicy(b.Shoot.ExternalDomain.Provider, policy); err != nil {
+			return nil, err
+		}
+
+		return dns.NewDNSEntry(
+			&dns.EntryValues{
+				Name:          DNSExternalName,
+				DNSName:       *b.Shoot.ExternalClusterDomain,
+				Targets:       []string{b.APIServerAddress},
+				TTL:           b.dnsTTL(),
+				Interval:      b.dnsInterval(),
+				RoutingPolicy: toEntryRoutingPolicy(policy),
+				Realms:        []string{GenerateDNSRealm(b.Shoot.SeedNamespace)},
+			},
+			b.Shoot.SeedNamespace,
+			b.ChartApplierSeed,
+			b.ChartsRootPath,
+			b.Logger,
+			seedClient,
+			nil,
+		), nil
+	}
+
 	return component.OpDestroy(dns.NewDNSEntry(
 		&dns.EntryValues{
 			Name: DNSExternalName,
@@ -151,7 +251,7 @@ func (b *Botanist) DefaultExternalDNSEntry(seedClient client.Client) component.D
 		b.Logger,
 		seedClient,
 		nil,
-	))
+	)), nil
 }
 
 // DefaultInternalDNSProvider returns the internal DNSProvider if internal DNS is
@@ -195,8 +295,35 @@ func (b *Botanist) DefaultInternalDNSProvider(seedClient client.Client) componen
 	))
 }
 
-// DefaultInternalDNSEntry returns DeployWaiter which removes the internal DNSEntry.
-func (b *Botanist) DefaultInternalDNSEntry(seedClient client.Client) component.DeployWaiter {
+// DefaultInternalDNSEntry returns the internal DNSEntry if internal DNS is enabled and if not, a
+// DeployWaiter which removes the internal DNSEntry. It returns an error if the shoot's routing policy
+// is not supported by the internal DNS provider type.
+func (b *Botanist) DefaultInternalDNSEntry(seedClient client.Client) (component.DeployWaiter, error) {
+	if b.NeedsInternalDNS() {
+		policy := b.primaryRoutingPolicy()
+		if err := validateRoutingPolicy(b.Garden.InternalDomain.Provider, policy); err != nil {
+			return nil, err
+		}
+
+		return dns.NewDNSEntry(
+			&dns.EntryValues{
+				Name:          DNSInternalName,
+				DNSName:       b.Shoot.InternalClusterDomain,
+				Targets:       []string{b.APIServerAddress},
+				TTL:           b.dnsTTL(),
+				Interval:      b.dnsInterval(),
+				RoutingPolicy: toEntryRoutingPolicy(policy),
+				Realms:        []string{GenerateDNSRealm(b.Shoot.SeedNamespace)},
+			},
+			b.Shoot.SeedNamespace,
+			b.ChartApplierSeed,
+			b.ChartsRootPath,
+			b.Logger,
+			seedClient,
+			nil,
+		), nil
+	}
+
 	return component.OpDestroy(dns.NewDNSEntry(
 		&dns.EntryValues{
 			Name: DNSInternalName,
@@ -207,9 +334,15 @@ func (b *Botanist) DefaultInternalDNSEntry(seedClient client.Client) component.D
 		b.Logger,
 		seedClient,
 		nil,
-	))
+	)), nil
 }
 
+// DefaultAdditionalDNSProvidersConcurrency is the number of additional DNS providers for which
+// secrets are fetched and deploy configuration is assembled concurrently. It is a package-level
+// variable rather than a constant so that it is configurable, e.g. by an operator wiring it up to
+// gardenlet component configuration, or by a test that wants to exercise a specific concurrency.
+var DefaultAdditionalDNSProvidersConcurrency = 10
+
 // AdditionalDNSProviders returns a map containing DNSProviders where the key is the provider name.
 // Providers and DNSEntries which are no longer needed / or in use, contain a DeployWaiter which removes
 // said DNSEntry / DNSProvider.
@@ -217,71 +350,103 @@ func (b *Botanist) AdditionalDNSProviders(ctx context.Context, gardenClient, see
 	additionalProviders := map[string]component.DeployWaiter{}
 
 	if b.NeedsAdditionalDNSProviders() {
-		for i, provider := range b.Shoot.Info.Spec.DNS.Providers {
-			p := provider
-			if p.Primary != nil && *p.Primary {
-				continue
-			}
-
-			var includeDomains, excludeDomains, includeZones, excludeZones []string
-			if domains := p.Domains; domains != nil {
-				includeDomains = domains.Include
-				excludeDomains = domains.Exclude
-			}
-
-			if zones := p.Zones; zones != nil {
-				includeZones = zones.Include
-				excludeZones = zones.Exclude
-			}
+		var (
+			mutex sync.Mutex
+			sem   = make(chan struct{}, DefaultAdditionalDNSProvidersConcurrency)
+		)
 
-			providerType := p.Type
-			if providerType == nil {
-				return nil, fmt.Errorf("dns provider[%d] doesn't specify a type", i)
-			}
+		g, ctx := errgroup.WithContext(ctx)
 
-			if *providerType == core.DNSUnmanaged {
-				b.Logger.Infof("Skipping deployment of DNS provider[%d] since it specifies type %q", i, core.DNSUnmanaged)
+		for i, provider := range b.Shoot.Info.Spec.DNS.Providers {
+			i, p := i, provider
+			if p.Primary != nil && *p.Primary {
 				continue
 			}
 
-			secretName := p.SecretName
-			if secretName == nil {
-				return nil, fmt.Errorf("dns provider[%d] doesn't specify a secretName", i)
-			}
-
-			secret := &corev1.Secret{}
-			if err := gardenClient.Get(
-				ctx,
-				kutil.Key(b.Shoot.Info.Namespace, *secretName),
-				secret,
-			); err != nil {
-				return nil, fmt.Errorf("could not get dns provider secret %q: %+v", *secretName, err)
-			}
-			providerName := GenerateDNSProviderName(*secretName, *providerType)
-
-			additionalProviders[providerName] = dns.NewDNSProvider(
-				&dns.ProviderValues{
-					Name:       providerName,
-					Purpose:    providerName,
-					Labels:     map[string]string{v1beta1constants.GardenRole: DNSProviderRoleAdditional},
-					SecretData: secret.Data,
-					Provider:   *p.Type,
-					Domains: &dns.IncludeExclude{
-						Include: includeDomains,
-						Exclude: excludeDomains,
-					},
-					Zones: &dns.IncludeExclude{
-						Include: includeZones,
-						Exclude: excludeZones,
+			g.Go(func() error {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				var includeDomains, excludeDomains, includeZones, excludeZones []string
+				if domains := p.Domains; domains != nil {
+					includeDomains = domains.Include
+					excludeDomains = domains.Exclude
+				}
+
+				if zones := p.Zones; zones != nil {
+					includeZones = zones.Include
+					excludeZones = zones.Exclude
+				}
+
+				providerType := p.Type
+				if providerType == nil {
+					return fmt.Errorf("dns provider[%d] doesn't specify a type", i)
+				}
+
+				if *providerType == core.DNSUnmanaged {
+					b.Logger.Infof("Skipping deployment of DNS provider[%d] since it specifies type %q", i, core.DNSUnmanaged)
+					return nil
+				}
+
+				if err := validateRoutingPolicy(*providerType, p.RoutingPolicy); err != nil {
+					return fmt.Errorf("dns provider[%d] is invalid: %+v", i, err)
+				}
+
+				secretName := p.SecretName
+				if secretName == nil {
+					return fmt.Errorf("dns provider[%d] doesn't specify a secretName", i)
+				}
+
+				secret := &corev1.Secret{}
+				if err := gardenClient.Get(
+					ctx,
+					kutil.Key(b.Shoot.Info.Namespace, *secretName),
+					secret,
+				); err != nil {
+					return fmt.Errorf("could not get dns provider secret %q: %+v", *secretName, err)
+				}
+				providerName := GenerateDNSProviderName(*secretName, *providerType)
+
+				realms := p.Realms
+				if len(realms) == 0 {
+					realms = []string{GenerateDNSRealm(b.Shoot.SeedNamespace)}
+				}
+
+				provider := dns.NewDNSProvider(
+					&dns.ProviderValues{
+						Name:       providerName,
+						Purpose:    providerName,
+						Labels:     map[string]string{v1beta1constants.GardenRole: DNSProviderRoleAdditional},
+						SecretData: secret.Data,
+						Provider:   *p.Type,
+						Domains: &dns.IncludeExclude{
+							Include: includeDomains,
+							Exclude: excludeDomains,
+						},
+						Zones: &dns.IncludeExclude{
+							Include: includeZones,
+							Exclude: excludeZones,
+						},
+						Realms: realms,
 					},
-				},
-				b.Shoot.SeedNamespace,
-				b.ChartApplierSeed,
-				b.ChartsRootPath,
-				b.Logger,
-				seedClient,
-				nil,
-			)
+					b.Shoot.SeedNamespace,
+					b.ChartApplierSeed,
+					b.ChartsRootPath,
+					b.Logger,
+					seedClient,
+					nil,
+				)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+				additionalProviders[providerName] = provider
+
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return nil, err
 		}
 	}
 
@@ -0,0 +1,111 @@
+// Copyright (c) 2018 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package botanist
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	dnsv1alpha1 "github.com/gardener/external-dns-management/pkg/apis/dns/v1alpha1"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/gardener/gardener/pkg/logger"
+	shootpkg "github.com/gardener/gardener/pkg/operation/shoot"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// latencyInjectingClient wraps a client.Client and sleeps for a fixed duration before every Get, to
+// simulate a garden API server with non-trivial request latency.
+type latencyInjectingClient struct {
+	client.Client
+	latency time.Duration
+}
+
+func (c *latencyInjectingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	time.Sleep(c.latency)
+	return c.Client.Get(ctx, key, obj)
+}
+
+func TestAdditionalDNSProvidersParallelizesSecretFetch(t *testing.T) {
+	const (
+		numProviders = 8
+		latency      = 50 * time.Millisecond
+		providerType = "aws-route53"
+	)
+
+	var (
+		objs      []runtime.Object
+		providers []gardencorev1beta1.DNSProvider
+	)
+
+	for i := 0; i < numProviders; i++ {
+		secretName := fmt.Sprintf("dns-secret-%d", i)
+		objs = append(objs, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "garden-myproject"},
+			Data:       map[string][]byte{"foo": []byte("bar")},
+		})
+
+		pType, pSecretName := providerType, secretName
+		providers = append(providers, gardencorev1beta1.DNSProvider{
+			Type:       &pType,
+			SecretName: &pSecretName,
+		})
+	}
+
+	gardenClient := &latencyInjectingClient{
+		Client:  fakeclient.NewClientBuilder().WithRuntimeObjects(objs...).Build(),
+		latency: latency,
+	}
+	seedScheme := runtime.NewScheme()
+	utilruntime.Must(dnsv1alpha1.AddToScheme(seedScheme))
+	seedClient := fakeclient.NewClientBuilder().WithScheme(seedScheme).Build()
+
+	b := &Botanist{}
+	b.Shoot = &shootpkg.Shoot{
+		SeedNamespace: "shoot--myproject--mygarden",
+	}
+	b.Shoot.Info = &gardencorev1beta1.Shoot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "garden-myproject"},
+		Spec: gardencorev1beta1.ShootSpec{
+			DNS: &gardencorev1beta1.DNS{Providers: providers},
+		},
+	}
+	b.Logger = logger.NewNopLogger()
+
+	start := time.Now()
+	result, err := b.AdditionalDNSProviders(context.Background(), gardenClient, seedClient)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("AdditionalDNSProviders returned error: %v", err)
+	}
+	if len(result) != numProviders {
+		t.Fatalf("got %d providers, want %d", len(result), numProviders)
+	}
+
+	// A sequential implementation would take at least numProviders*latency. With
+	// DefaultAdditionalDNSProvidersConcurrency fan-out, wall time should stay well below that bound.
+	sequential := time.Duration(numProviders) * latency
+	if elapsed >= sequential {
+		t.Fatalf("AdditionalDNSProviders took %s, expected well under the sequential bound of %s for %d providers fetched with concurrency %d", elapsed, sequential, numProviders, DefaultAdditionalDNSProvidersConcurrency)
+	}
+}
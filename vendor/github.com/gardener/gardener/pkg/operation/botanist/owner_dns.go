@@ -0,0 +1,156 @@
+// Copyright (c) 2021 SAP SE or an SAP affiliate company. All rights reserved. This file is licensed under the Apache Software License, v. 2 except as noted otherwise in the LICENSE file
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package botanist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	"github.com/gardener/gardener/pkg/operation/botanist/component"
+	"github.com/gardener/gardener/pkg/operation/botanist/dns"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DNSOwnerName is the name of the DNSEntry that carries the owning seed's cluster identity.
+	DNSOwnerName = "owner"
+	// DNSOwnerRole is the GardenRole label value applied to a shoot's owner DNSEntry. It identifies
+	// the resource for humans/tooling inspecting the seed namespace; it is not used to enumerate
+	// owner DNSEntries across seeds (each seed is a separate cluster and cannot list another seed's
+	// objects), so discovering/cleaning up a stale owner DNSEntry left behind by a prior seed remains
+	// that prior seed's own responsibility during its own migration-out.
+	DNSOwnerRole = "owner-dns"
+)
+
+// publicDNSResolvers are used to resolve the owner DNS record independently of the seed cluster's
+// in-cluster DNS, so that a stale, cached answer cannot fool a migrating seed into believing it
+// already owns the shoot.
+var publicDNSResolvers = []string{
+	"8.8.8.8:53",
+	"1.1.1.1:53",
+	"9.9.9.9:53",
+}
+
+// OwnerID returns the cluster identity of the seed the shoot is currently being reconciled from, or
+// the empty string if the seed does not have a cluster identity.
+func (b *Botanist) OwnerID() string {
+	if identity := b.Seed.GetInfo().Status.ClusterIdentity; identity != nil {
+		return *identity
+	}
+	return ""
+}
+
+// ownerDNSName returns the fully qualified domain name of the owner TXT DNSEntry.
+func (b *Botanist) ownerDNSName() string {
+	return fmt.Sprintf("%s.%s", DNSOwnerName, b.Shoot.InternalClusterDomain)
+}
+
+// NeedsOwnerDNS returns true if the owner DNSEntry is needed, symmetric to NeedsInternalDNS.
+func (b *Botanist) NeedsOwnerDNS() bool {
+	return !b.Shoot.DisableDNS &&
+		b.Garden.InternalDomain != nil &&
+		b.Garden.InternalDomain.Provider != "unmanaged" &&
+		b.OwnerID() != ""
+}
+
+// DefaultOwnerDNSRecord returns a DeployWaiter which deploys the TXT DNSEntry carrying this seed's
+// identity as the current owner of the shoot's control plane under the shoot's internal domain.
+func (b *Botanist) DefaultOwnerDNSRecord(seedClient client.Client) component.DeployWaiter {
+	return dns.NewDNSEntry(
+		&dns.EntryValues{
+			Name:    DNSOwnerName,
+			DNSName: b.ownerDNSName(),
+			Text:    []string{b.OwnerID()},
+			TTL:     b.dnsTTL(),
+		},
+		b.Shoot.SeedNamespace,
+		b.ChartApplierSeed,
+		b.ChartsRootPath,
+		b.Logger,
+		seedClient,
+		map[string]string{v1beta1constants.GardenRole: DNSOwnerRole},
+	)
+}
+
+// DeployOwnerDNS deploys the owner DNSEntry if it is needed, and removes it otherwise.
+func (b *Botanist) DeployOwnerDNS(ctx context.Context) error {
+	if !b.NeedsOwnerDNS() {
+		return b.DestroyOwnerDNS(ctx)
+	}
+
+	return b.DefaultOwnerDNSRecord(b.K8sSeedClient.Client()).Deploy(ctx)
+}
+
+// DestroyOwnerDNS idempotently removes this seed's owner DNSEntry. Since the owner DNSEntry lives in
+// the seed cluster the shoot is being reconciled from, a seed can only ever clean up its own entry;
+// cleaning up a stale owner DNSEntry left behind by a prior seed is that seed's own responsibility as
+// it migrates the shoot away, not something a later seed's client can observe or act on.
+func (b *Botanist) DestroyOwnerDNS(ctx context.Context) error {
+	return b.DefaultOwnerDNSRecord(b.K8sSeedClient.Client()).Destroy(ctx)
+}
+
+// CheckOwnerDNSRecord resolves the owner TXT record from multiple public DNS resolvers and returns
+// true only if all of them agree that this seed already owns the shoot. It is used during "bad-case"
+// control-plane migration, where the new seed must refuse to reconcile the shoot until the old seed
+// has relinquished ownership by updating the record to the new seed's cluster identity.
+func (b *Botanist) CheckOwnerDNSRecord(ctx context.Context) (bool, error) {
+	ownerID := b.OwnerID()
+	if ownerID == "" {
+		return false, fmt.Errorf("seed does not have a cluster identity, cannot check dns ownership")
+	}
+
+	dnsName := b.ownerDNSName()
+
+	for _, resolverAddr := range publicDNSResolvers {
+		resolverAddr := resolverAddr
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: 5 * time.Second}
+				return dialer.DialContext(ctx, network, resolverAddr)
+			},
+		}
+
+		records, err := resolver.LookupTXT(ctx, dnsName)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+				// The owner record has not been created yet, e.g. because this is not a "bad-case"
+				// control-plane migration. This seed does not yet own the shoot.
+				return false, nil
+			}
+			return false, fmt.Errorf("could not resolve owner dns record %q via resolver %q: %+v", dnsName, resolverAddr, err)
+		}
+
+		owned := false
+		for _, record := range records {
+			if record == ownerID {
+				owned = true
+				break
+			}
+		}
+
+		if !owned {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}